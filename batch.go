@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	tunnel "disenroll-inverter/src"
+	"disenroll-inverter/src/dbops"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/joho/godotenv"
+)
+
+// macAddressPattern matches the conventional colon- or hyphen-separated MAC address form.
+var macAddressPattern = regexp.MustCompile(`^([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`)
+
+// runBatchMode implements `disenroll-inverter batch`: it reads a list of MAC addresses, validates
+// them, and disenrolls each one concurrently over a bounded worker pool that shares one SSH
+// tunnel and one DB connection, recording every step to a JSONL audit log.
+func runBatchMode() {
+
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	macsFile := fs.String("file", "", "newline-delimited file of MAC addresses (default: stdin)")
+	dryRun := fs.Bool("dry-run", false, "run all checks but skip every delete")
+	auditPath := fs.String("audit-log", "audit.jsonl", "path to the JSONL audit log")
+	workers := fs.Int("workers", 4, "number of MAC addresses to process concurrently")
+	fs.Parse(os.Args[2:])
+
+	macs, err := readMACs(*macsFile)
+	if err != nil {
+		log.Fatalf("failed to read MAC addresses: %v", err)
+	}
+
+	valid := make([]string, 0, len(macs))
+	for _, mac := range macs {
+		if macAddressPattern.MatchString(mac) {
+			valid = append(valid, mac)
+		} else {
+			log.Printf("skipping invalid MAC address %q", mac)
+		}
+	}
+	if len(valid) == 0 {
+		log.Fatalf("no valid MAC addresses to process")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("error loading .env file")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sshTunnel, db, err := connectTunnelAndDB(ctx)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer db.Close()
+
+	audit, err := dbops.NewAuditLog(*auditPath)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer audit.Close()
+
+	dynamoClient := dbops.InitDynamoClient()
+
+	tables := batchTables{
+		heidiTable1:  os.Getenv("HEIDITABLE1"),
+		heidiTable2:  os.Getenv("HEIDITABLE2"),
+		dynamoTable1: os.Getenv("AWSDYNAMOTABLE1"),
+		dynamoTable2: os.Getenv("AWSDYNAMOTABLE2"),
+	}
+
+	sem := make(chan struct{}, *workers)
+	var wg sync.WaitGroup
+
+	for _, mac := range valid {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(mac string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			processMAC(ctx, db, dynamoClient, mac, tables, *dryRun, audit)
+		}(mac)
+	}
+	wg.Wait()
+
+	fmt.Printf("\nBatch disenrollment complete: %d MAC address(es) processed.\n", len(valid))
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), sshTunnel.Opts.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := sshTunnel.Shutdown(shutdownCtx); err != nil {
+		log.Printf("tunnel shutdown: %v", err)
+	}
+}
+
+// batchTables names the tables and caches touched by processMAC.
+type batchTables struct {
+	heidiTable1  string
+	heidiTable2  string
+	dynamoTable1 string
+	dynamoTable2 string
+}
+
+// processMAC runs the same disenrollment sequence as the interactive flow for a single MAC
+// address, recording each step to audit, skipping every delete under dryRun, and rolling table 1
+// back if the table 2 delete fails partway through.
+func processMAC(ctx context.Context, db *sql.DB, dynamoClient *dynamodb.Client, mac string, tables batchTables, dryRun bool, audit *dbops.AuditLog) {
+
+	inverter := &dbops.Inverter{MAC: mac}
+
+	enrolled := inverter.CheckIfExists(db, tables.heidiTable1)
+	if !enrolled {
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "check", Result: "not enrolled"})
+		return
+	}
+	audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "check", Result: "enrolled"})
+
+	if dryRun {
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "delete", Result: "dry-run"})
+		if inverter.CheckIfExists(db, tables.heidiTable2) {
+			audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable2, Action: "delete", Result: "dry-run"})
+		} else {
+			audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable2, Action: "check", Result: "does not exist"})
+		}
+		return
+	}
+
+	// Snapshot table 1 before deleting so it can be restored if the table 2 delete fails and
+	// leaves the two tables (and DynamoDB) inconsistent. dbops.MACColumn is the same column
+	// DeleteMacFromTable matches against, so the snapshot always covers exactly the rows the
+	// delete is about to remove.
+	snapshot, err := dbops.SnapshotRows(db, tables.heidiTable1, dbops.MACColumn, mac)
+	if err != nil {
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "snapshot", Result: "error", Error: err.Error()})
+		return
+	}
+	if len(snapshot) == 0 {
+		// CheckIfExists just confirmed mac is enrolled, so an empty snapshot means it didn't
+		// actually capture that row. Deleting now would leave no way to roll back.
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "snapshot", Result: "error", Error: "snapshot captured zero rows for an enrolled MAC"})
+		return
+	}
+
+	if err := inverter.DeleteMacFromTable(db, tables.heidiTable1); err != nil {
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "delete", Result: "error", Error: err.Error()})
+		return
+	}
+	audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "delete", Result: "ok"})
+
+	if inverter.CheckIfExists(db, tables.heidiTable2) {
+		if err := inverter.DeleteMacFromTable(db, tables.heidiTable2); err != nil {
+			audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable2, Action: "delete", Result: "error", Error: err.Error()})
+
+			if restoreErr := dbops.RestoreRows(db, tables.heidiTable1, snapshot); restoreErr != nil {
+				audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "rollback", Result: "error", Error: restoreErr.Error()})
+			} else {
+				audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable1, Action: "rollback", Result: "ok"})
+			}
+			return
+		}
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable2, Action: "delete", Result: "ok"})
+	} else {
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: tables.heidiTable2, Action: "check", Result: "does not exist"})
+	}
+
+	for _, cache := range []string{tables.dynamoTable1, tables.dynamoTable2} {
+		if err := inverter.DeleteMacFromDynamoDBTable(ctx, dynamoClient, cache); err != nil {
+			audit.Record(dbops.AuditEntry{MAC: mac, Table: cache, Action: "delete", Result: "error", Error: err.Error()})
+			continue
+		}
+		audit.Record(dbops.AuditEntry{MAC: mac, Table: cache, Action: "delete", Result: "ok"})
+	}
+}
+
+// readMACs reads newline-delimited MAC addresses from path, or from stdin if path is empty.
+// Blank lines are ignored.
+func readMACs(path string) ([]string, error) {
+
+	var f *os.File
+	if path == "" {
+		f = os.Stdin
+	} else {
+		opened, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s. Here's why: %w", path, err)
+		}
+		defer opened.Close()
+		f = opened
+	}
+
+	var macs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		macs = append(macs, line)
+	}
+
+	return macs, scanner.Err()
+}
+
+// connectTunnelAndDB establishes the SSH tunnel and HeidiSQL connection the same way the
+// interactive flow does, for reuse by batch mode.
+func connectTunnelAndDB(ctx context.Context) (*tunnel.SSHTunnel, *sql.DB, error) {
+
+	sshUser := os.Getenv("SSH_USER")
+	sshPrivateKey := os.Getenv("SSH_PRIVATE_KEY_PATH")
+	sshDestination := os.Getenv("SSH_DESTINATION")
+	sshKnownHosts := os.Getenv("SSH_KNOWN_HOSTS_FILE")
+	if sshKnownHosts == "" {
+		sshKnownHosts = "/.ssh/known_hosts"
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get home directory. Here's why: %w", err)
+	}
+
+	tunnelOpts := tunnel.DefaultTunnelOptions()
+	tunnelOpts.HostKeyMode = tunnel.KnownHosts
+	tunnelOpts.KnownHostsFiles = []string{homeDir + sshKnownHosts}
+
+	sshTunnel, err := tunnel.NewSSHTunnel(
+		sshUser,
+		tunnel.PrivateKeyFile(homeDir+sshPrivateKey),
+		sshDestination,
+		&tunnelOpts,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to configure SSH tunnel. Here's why: %w", err)
+	}
+
+	go func() {
+		if err := sshTunnel.Start(ctx); err != nil {
+			log.Printf("SSH tunnel exited: %v", err)
+		}
+	}()
+
+	if err := sshTunnel.WaitReady(ctx); err != nil {
+		return nil, nil, fmt.Errorf("error waiting for tunnel. Here's why: %w", err)
+	}
+
+	dbName := os.Getenv("DBNAME")
+	dbUsername := os.Getenv("DB_USERNAME")
+	dbPassword := os.Getenv("DB_PASSWORD")
+	connStr := fmt.Sprintf("%s:%s@tcp(127.0.0.1:%d)/%s", dbUsername, dbPassword, sshTunnel.Local.Port, dbName)
+
+	db, err := sql.Open("mysql", connStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database. Here's why: %w", err)
+	}
+
+	pingCtx, cancelPing := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelPing()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to ping database. Here's why: %w", err)
+	}
+
+	return sshTunnel, db, nil
+}
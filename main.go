@@ -22,6 +22,16 @@ import (
 
 func main(){
 
+	if len(os.Args) > 1 && os.Args[1] == "tunnels" {
+		runTunnelsDaemon()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "batch" {
+		runBatchMode()
+		return
+	}
+
 	// Load environment variables from .env file
 	err := godotenv.Load()
 	if err != nil {
@@ -31,6 +41,10 @@ func main(){
 	sshUser := os.Getenv("SSH_USER")
 	sshPrivateKey := os.Getenv("SSH_PRIVATE_KEY_PATH")
 	sshDestination := os.Getenv("SSH_DESTINATION")
+	sshKnownHosts := os.Getenv("SSH_KNOWN_HOSTS_FILE")
+	if sshKnownHosts == "" {
+		sshKnownHosts = "/.ssh/known_hosts"
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -51,11 +65,19 @@ func main(){
 
 	// Start SSH Tunnel
 	// Setup tunnel, do not start it yet
-	sshTunnel := tunnel.NewSSHTunnel(
+	tunnelOpts := tunnel.DefaultTunnelOptions()
+	tunnelOpts.HostKeyMode = tunnel.KnownHosts
+	tunnelOpts.KnownHostsFiles = []string{homeDir + sshKnownHosts}
+
+	sshTunnel, err := tunnel.NewSSHTunnel(
 		sshUser, // user@host, default port is 22 if not specified
 		tunnel.PrivateKeyFile(homeDir+sshPrivateKey), // Auth via private key
 		sshDestination,
+		&tunnelOpts, // Verify Server's host key against SSH_KNOWN_HOSTS_FILE.
 	)
+	if err != nil {
+		log.Fatalf("\nFailed to configure SSH tunnel. Here's why: %v", err)
+	}
 
 	// Start SSH Tunnel in its goroutine, use context for handling shutdown.
 	fmt.Printf("Starting SSH Tunnel...")
@@ -72,11 +94,50 @@ func main(){
 	}
 	fmt.Printf("Tunnel established successfully on %s.\n", sshTunnel.Local)
 
+	// Capture the local port before the signal goroutine starts reassigning sshTunnel on SIGHUP:
+	// recycleTunnel keeps the same port across recycles, so this stays valid for the DB connection
+	// string below without racing that goroutine's writes to the sshTunnel variable.
+	tunnelLocalPort := sshTunnel.Local.Port
+
+	// Handle signals for the rest of the program's life. SIGINT/SIGTERM drain the tunnel and
+	// shut everything down; SIGHUP drains and recycles the tunnel in place, without ever
+	// cancelling ctx, so the program keeps running on the same local port instead of exiting.
+	// See recycleTunnel for what that recycle does and doesn't guarantee for in-flight work.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	shutdownComplete := make(chan struct{})
+
+	go func() {
+		defer close(shutdownComplete)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("context canceled, shutting down...")
+				return
+			case s := <-sigCh:
+				if s == syscall.SIGHUP {
+					log.Printf("\nReceived SIGHUP, draining and recycling SSH tunnel...")
+					sshTunnel = recycleTunnel(ctx, sshTunnel, sshUser, sshPrivateKey, sshDestination, homeDir)
+					continue
+				}
+
+				log.Printf("\nReceived signal %s, shutting down...", s)
+				shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), sshTunnel.Opts.ShutdownTimeout)
+				if err := sshTunnel.Shutdown(shutdownCtx); err != nil {
+					log.Printf("\ntunnel shutdown: %v", err)
+				}
+				cancelShutdown()
+				cancel() // shut down context
+				return
+			}
+		}
+	}()
+
 	// Open HeidiSQL Connection
 	dbName := os.Getenv("DBNAME")
 	dbUsername := os.Getenv("DB_USERNAME")
 	dbPassword := os.Getenv("DB_PASSWORD")
-	connStr := fmt.Sprintf("%s:%s@tcp(127.0.0.1:%d)/%s", dbUsername, dbPassword, sshTunnel.Local.Port, dbName)
+	connStr := fmt.Sprintf("%s:%s@tcp(127.0.0.1:%d)/%s", dbUsername, dbPassword, tunnelLocalPort, dbName)
 
 	fmt.Printf("Connecting to HeidiSQL...")
 	db, err := sql.Open("mysql", connStr)
@@ -165,15 +226,130 @@ func main(){
 	}	
 	fmt.Printf("Done [6/6]")
 
-	// Block main until signal of shutdown.
+	// Block main until a shutdown signal is handled.
+	<-shutdownComplete
+}
+
+// recycleTunnel drains old and replaces it with a freshly dialed tunnel bound to the same local
+// port, so a subsequent connection to that port picks up the new tunnel without the DB code having
+// to learn a new address. old.Shutdown gives in-flight forwarded connections until
+// old.Opts.ShutdownTimeout to finish on their own before force-closing them, so any DB session (and
+// in-flight transaction) still using old when SIGHUP arrives is dropped, not preserved, once that
+// deadline passes. If the replacement can't be brought up, old is returned unchanged (already
+// drained, but not accepting new connections) and the caller should expect the DB connection to
+// start failing.
+func recycleTunnel(ctx context.Context, old *tunnel.SSHTunnel, sshUser, sshPrivateKey, sshDestination, homeDir string) *tunnel.SSHTunnel {
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), old.Opts.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := old.Shutdown(shutdownCtx); err != nil {
+		log.Printf("\ntunnel drain before recycle: %v", err)
+	}
+
+	fresh, err := tunnel.NewSSHTunnel(
+		sshUser,
+		tunnel.PrivateKeyFile(homeDir+sshPrivateKey),
+		sshDestination,
+		&old.Opts,
+	)
+	if err != nil {
+		log.Printf("\nfailed to recreate SSH tunnel, it will stay down: %v", err)
+		return old
+	}
+	fresh.Local.Port = old.Local.Port
+
+	go func() {
+		if err := fresh.Start(ctx); err != nil {
+			log.Printf("\nrecycled SSH tunnel exited: %v", err)
+		}
+	}()
+
+	if err := fresh.WaitReady(ctx); err != nil {
+		log.Printf("\nfailed to bring recycled tunnel back up: %v", err)
+		return old
+	}
+
+	fmt.Printf("\nTunnel recycled on %s.\n", fresh.Local)
+	return fresh
+}
+
+// runTunnelsDaemon runs `disenroll-inverter tunnels`: it loads a multi-tunnel config file, starts
+// every tunnel in it under one TunnelManager, opens any DB targets declared alongside them, and
+// keeps running, printing status periodically, until it's asked to stop. SIGHUP reloads the
+// tunnels in place; SIGINT/SIGTERM shut everything down.
+func runTunnelsDaemon() {
+
+	configPath := os.Getenv("TUNNELS_CONFIG")
+	if configPath == "" {
+		configPath = "tunnels.json"
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Reload and Open below only log failures and keep going: this is a long-lived daemon, and one
+	// unreachable tunnel server or misconfigured DB target shouldn't take down every other tunnel
+	// and DB target that's working fine. This matches TunnelManager.Reload's own "keep going,
+	// report the first error" semantics instead of undercutting them with a fatal exit.
+	manager := tunnel.NewTunnelManager(ctx)
+	if err := manager.Reload(configPath); err != nil {
+		log.Printf("tunnel config %s: %v", configPath, err)
+	}
+
+	dbTargets, err := dbops.LoadDBTargets(configPath)
+	if err != nil {
+		log.Printf("failed to load DB targets from %s. Here's why: %v", configPath, err)
+	}
+	var openDBs []*sql.DB
+	defer func() {
+		for _, db := range openDBs {
+			db.Close()
+		}
+	}()
+	for _, target := range dbTargets {
+		db, err := dbops.Open(manager, target)
+		if err != nil {
+			log.Printf("failed to open DB target %q. Here's why: %v", target.Name, err)
+			continue
+		}
+		openDBs = append(openDBs, db)
+		log.Printf("DB target %q open via tunnel %q", target.Name, target.Tunnel)
+	}
+
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-
-	select {
-	case <- ctx.Done():
-		log.Println("context canceled, shutting down...")
-	case s := <- sigCh:
-		log.Printf("\nReceived signal %s, shutting down...", s)
-		cancel() // shut down context
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	statusTicker := time.NewTicker(10 * time.Second)
+	defer statusTicker.Stop()
+
+	printTunnelStatus(manager.Status())
+
+	for {
+		select {
+		case <-statusTicker.C:
+			printTunnelStatus(manager.Status())
+
+		case s := <-sigCh:
+			if s == syscall.SIGHUP {
+				log.Printf("received SIGHUP, reloading %s...", configPath)
+				if err := manager.Reload(configPath); err != nil {
+					log.Printf("reload failed: %v", err)
+				}
+				printTunnelStatus(manager.Status())
+				continue
+			}
+
+			log.Printf("received %s, shutting down tunnels...", s)
+			cancel()
+			return
+		}
+	}
+}
+
+// printTunnelStatus prints one line per tunnel managed by the daemon.
+func printTunnelStatus(statuses []tunnel.TunnelStatus) {
+	for _, s := range statuses {
+		fmt.Printf("[%s] server=%s healthy=%v active=%d last_connected=%s\n",
+			s.Name, s.Server, s.Healthy, s.ActiveConnections, s.LastConnected.Format(time.RFC3339))
 	}
 }
@@ -0,0 +1,108 @@
+package tunnel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// TunnelConfig describes one tunnel entry in a multi-tunnel config file. See LoadConfig.
+type TunnelConfig struct {
+    Name            string   `json:"name"`
+    Server          string   `json:"server"`                    // "user@host[:port]"
+    Mode            string   `json:"mode"`                       // "forward" (default) or "reverse"
+    Local           string   `json:"local"`                      // forward: "host:port" to listen on, defaults to "localhost:0". reverse: local dial target.
+    Remote          string   `json:"remote"`                     // forward: destination reached via server. reverse: bind address on server.
+    KeyFiles        []string `json:"keyFiles"`                   // Private key files tried in order.
+    KnownHostsFiles []string `json:"knownHostsFiles,omitempty"`  // Enables HostKeyMode KnownHosts if non-empty.
+    TOFUFile        string   `json:"tofuFile,omitempty"`         // Trust-on-first-use file for KnownHosts mode.
+    KeepAlive       string   `json:"keepAlive,omitempty"`        // Keepalive interval, e.g. "30s". Defaults to DefaultTunnelOptions.
+    FailFast        bool     `json:"failFast,omitempty"`
+}
+
+// tunnelsFile is the top-level shape of a multi-tunnel config file.
+type tunnelsFile struct {
+    Tunnels []TunnelConfig `json:"tunnels"`
+}
+
+// LoadConfig reads a JSON multi-tunnel config file of the form {"tunnels": [...]}.
+func LoadConfig(path string) ([]TunnelConfig, error) {
+
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read tunnel config %s. Here's why: %w", path, err)
+    }
+
+    var file tunnelsFile
+    if err := json.Unmarshal(data, &file); err != nil {
+        return nil, fmt.Errorf("failed to parse tunnel config %s. Here's why: %w", path, err)
+    }
+
+    for i, cfg := range file.Tunnels {
+        if cfg.Name == "" {
+            return nil, fmt.Errorf("tunnel config %s: entry %d is missing a name", path, i)
+        }
+    }
+
+    return file.Tunnels, nil
+}
+
+// Build turns a TunnelConfig into a not-yet-started SSHTunnel, trying each of KeyFiles in order
+// for the first one that parses.
+func (cfg TunnelConfig) Build() (*SSHTunnel, error) {
+
+    auth, err := firstValidPrivateKey(cfg.KeyFiles)
+    if err != nil {
+        return nil, fmt.Errorf("tunnel %q: %w", cfg.Name, err)
+    }
+
+    opts := DefaultTunnelOptions()
+    opts.FailFast = cfg.FailFast
+
+    if cfg.KeepAlive != "" {
+        interval, err := time.ParseDuration(cfg.KeepAlive)
+        if err != nil {
+            return nil, fmt.Errorf("tunnel %q: invalid keepAlive %q. Here's why: %w", cfg.Name, cfg.KeepAlive, err)
+        }
+        opts.KeepAliveInterval = interval
+    }
+
+    if len(cfg.KnownHostsFiles) > 0 {
+        opts.HostKeyMode = KnownHosts
+        opts.KnownHostsFiles = cfg.KnownHostsFiles
+        opts.TOFUFile = cfg.TOFUFile
+    }
+
+    if cfg.Mode == "reverse" {
+        return NewReverseSSHTunnel(cfg.Server, auth, cfg.Remote, cfg.Local, &opts)
+    }
+
+    t, err := NewSSHTunnel(cfg.Server, auth, cfg.Remote, &opts)
+    if err != nil {
+        return nil, err
+    }
+    if cfg.Local != "" {
+        t.Local = NewEndpoint(cfg.Local)
+    }
+    return t, nil
+}
+
+// firstValidPrivateKey returns the ssh.AuthMethod for the first file in paths that parses as a
+// private key.
+func firstValidPrivateKey(paths []string) (ssh.AuthMethod, error) {
+
+    if len(paths) == 0 {
+        return nil, fmt.Errorf("no keyFiles configured")
+    }
+
+    for _, path := range paths {
+        if auth := Private_key_file(path); auth != nil {
+            return auth, nil
+        }
+    }
+
+    return nil, fmt.Errorf("none of %v parsed as a private key", paths)
+}
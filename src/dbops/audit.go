@@ -0,0 +1,59 @@
+package dbops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry is one line of a batch disenrollment's JSONL audit log.
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	MAC       string    `json:"mac"`
+	Table     string    `json:"table"`
+	Action    string    `json:"action"` // e.g. "check", "snapshot", "delete", "rollback".
+	Result    string    `json:"result"` // e.g. "ok", "error", "dry-run", "not enrolled".
+	Error     string    `json:"error,omitempty"`
+}
+
+// AuditLog appends AuditEntry records as JSON lines to a file. Safe for concurrent use.
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewAuditLog opens (creating if necessary) the JSONL audit log at path, appending to any
+// existing content.
+func NewAuditLog(path string) (*AuditLog, error) {
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s. Here's why: %w", path, err)
+	}
+
+	return &AuditLog{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record appends entry to the log, stamping it with the current time if Timestamp is zero.
+func (a *AuditLog) Record(entry AuditEntry) error {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	if err := a.enc.Encode(entry); err != nil {
+		return fmt.Errorf("failed to write audit entry for %s. Here's why: %w", entry.MAC, err)
+	}
+	return nil
+}
+
+// Close closes the underlying file.
+func (a *AuditLog) Close() error {
+	return a.file.Close()
+}
@@ -0,0 +1,83 @@
+package dbops
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// MACColumn is the column name DeleteMacFromTable matches a MAC address against. Callers that
+// need to look up or snapshot the same rows DeleteMacFromTable would delete must use this
+// constant rather than a literal column name, so the two never drift apart.
+const MACColumn = "mac_address"
+
+// SnapshotRows captures every row of table whose macColumn equals mac, as column-name-to-value
+// maps, so they can be restored later with RestoreRows if a later step in the same disenrollment
+// fails partway through.
+func SnapshotRows(db *sql.DB, table, macColumn, mac string) ([]map[string]any, error) {
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", table, macColumn)
+	rows, err := db.Query(query, mac)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s for %s. Here's why: %w", table, mac, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s. Here's why: %w", table, err)
+	}
+
+	var snapshot []map[string]any
+	for rows.Next() {
+
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan row of %s. Here's why: %w", table, err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, col := range columns {
+			row[col] = values[i]
+		}
+		snapshot = append(snapshot, row)
+	}
+
+	return snapshot, rows.Err()
+}
+
+// RestoreRows re-inserts every row captured by SnapshotRows back into table. An empty snapshot is
+// refused rather than treated as a trivial success: restoring nothing after a row was deleted
+// means the original snapshot never actually captured it (wrong macColumn, no matching row),
+// so reporting success here would silently mask a failed rollback.
+func RestoreRows(db *sql.DB, table string, snapshot []map[string]any) error {
+
+	if len(snapshot) == 0 {
+		return fmt.Errorf("dbops: refusing to restore %s from an empty snapshot", table)
+	}
+
+	for _, row := range snapshot {
+
+		columns := make([]string, 0, len(row))
+		placeholders := make([]string, 0, len(row))
+		values := make([]any, 0, len(row))
+
+		for col, val := range row {
+			columns = append(columns, col)
+			placeholders = append(placeholders, "?")
+			values = append(values, val)
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+		if _, err := db.Exec(query, values...); err != nil {
+			return fmt.Errorf("failed to restore row into %s. Here's why: %w", table, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package dbops
+
+import (
+	"database/sql"
+	tunnel "disenroll-inverter/src"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DBTarget names a MySQL database reached through a specific tunnel from the multi-tunnel config,
+// so a fleet of tunnels and the DB targets that use them can be declared side by side.
+type DBTarget struct {
+    Name     string `json:"name"`
+    Tunnel   string `json:"tunnel"` // Name of the tunnel in the TunnelManager to route through.
+    DBName   string `json:"dbName"`
+    Username string `json:"username"`
+    Password string `json:"password"`
+}
+
+// dbTargetsFile is the shape of the "dbTargets" array alongside "tunnels" in a multi-tunnel config
+// file. See tunnel.LoadConfig for the "tunnels" array in the same file.
+type dbTargetsFile struct {
+    DBTargets []DBTarget `json:"dbTargets"`
+}
+
+// LoadDBTargets reads the "dbTargets" array from a multi-tunnel config file.
+func LoadDBTargets(path string) ([]DBTarget, error) {
+
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, fmt.Errorf("dbops: failed to read tunnel config %s. Here's why: %w", path, err)
+    }
+
+    var file dbTargetsFile
+    if err := json.Unmarshal(data, &file); err != nil {
+        return nil, fmt.Errorf("dbops: failed to parse tunnel config %s. Here's why: %w", path, err)
+    }
+
+    return file.DBTargets, nil
+}
+
+// Open resolves target.Tunnel via manager and opens a MySQL connection through its local port.
+// target.Tunnel must name a forward-mode tunnel: in reverse mode, Local is the local dial target
+// the tunnel forwards accepted connections to, not a bound listener port, so there's no local port
+// to connect through.
+func Open(manager *tunnel.TunnelManager, target DBTarget) (*sql.DB, error) {
+
+    status, ok := manager.Lookup(target.Tunnel)
+    if !ok {
+        return nil, fmt.Errorf("dbops: no tunnel named %q for DB target %q", target.Tunnel, target.Name)
+    }
+
+    if status.Mode != tunnel.ModeForward {
+        return nil, fmt.Errorf("dbops: DB target %q routes through %q, which isn't a forward-mode tunnel", target.Name, target.Tunnel)
+    }
+
+    connStr := fmt.Sprintf("%s:%s@tcp(127.0.0.1:%d)/%s", target.Username, target.Password, status.Local.Port, target.DBName)
+
+    db, err := sql.Open("mysql", connStr)
+    if err != nil {
+        return nil, fmt.Errorf("dbops: failed to open DB target %q. Here's why: %w", target.Name, err)
+    }
+    return db, nil
+}
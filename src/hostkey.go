@@ -0,0 +1,136 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostKeyMode selects how SSHTunnel verifies the host key presented by Server.
+type HostKeyMode int
+
+const (
+    // InsecureIgnoreHostKey accepts any host key. Opt-in only, never silently defaulted to.
+    InsecureIgnoreHostKey HostKeyMode = iota
+
+    // KnownHosts verifies the presented key against TunnelOptions.KnownHostsFiles, optionally
+    // trusting new hosts on first use if TunnelOptions.TOFUFile is set.
+    KnownHosts
+
+    // PinnedFingerprint verifies the presented key's SHA256 fingerprint against
+    // TunnelOptions.PinnedFingerprints.
+    PinnedFingerprint
+)
+
+// HostKeyMismatchError is returned when the key presented by Server doesn't match what
+// verification expected, whether that's a known_hosts entry or a pinned fingerprint.
+type HostKeyMismatchError struct {
+    Host           string
+    GotFingerprint string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+    return fmt.Sprintf("ssh: host key mismatch for %s (got %s)", e.Host, e.GotFingerprint)
+}
+
+// buildHostKeyCallback constructs the ssh.HostKeyCallback for opts.HostKeyMode, to be installed
+// on the tunnel's ssh.ClientConfig.
+func buildHostKeyCallback(opts TunnelOptions) (ssh.HostKeyCallback, error) {
+
+    switch opts.HostKeyMode {
+
+    case KnownHosts:
+        return knownHostsCallback(opts)
+
+    case PinnedFingerprint:
+        return pinnedFingerprintCallback(opts.PinnedFingerprints), nil
+
+    case InsecureIgnoreHostKey:
+        return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+            // Always accept key. Opt-in only, see TunnelOptions.HostKeyMode.
+            return nil
+        }, nil
+
+    default:
+        return nil, fmt.Errorf("tunnel: unknown HostKeyMode %d", opts.HostKeyMode)
+    }
+}
+
+// knownHostsCallback verifies against opts.KnownHostsFiles, with optional trust-on-first-use
+// into opts.TOFUFile for hosts that aren't present in any of them yet.
+func knownHostsCallback(opts TunnelOptions) (ssh.HostKeyCallback, error) {
+
+    if len(opts.KnownHostsFiles) == 0 {
+        return nil, fmt.Errorf("tunnel: KnownHosts mode requires at least one entry in KnownHostsFiles")
+    }
+
+    callback, err := knownhosts.New(opts.KnownHostsFiles...)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load known_hosts files %v. Here's why: %w", opts.KnownHostsFiles, err)
+    }
+
+    return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+
+        err := callback(hostname, remote, key)
+        if err == nil {
+            return nil
+        }
+
+        var keyErr *knownhosts.KeyError
+        if !errors.As(err, &keyErr) {
+            return err
+        }
+
+        fingerprint := ssh.FingerprintSHA256(key)
+
+        // A non-empty Want list means the host is known but presented a different key: a
+        // mismatch, not an unknown host. Never fall through to TOFU in that case.
+        if len(keyErr.Want) > 0 || opts.TOFUFile == "" {
+            return &HostKeyMismatchError{Host: hostname, GotFingerprint: fingerprint}
+        }
+
+        log.Printf("\nTrusting new host %s on first use, fingerprint %s", hostname, fingerprint)
+        return appendKnownHost(opts.TOFUFile, hostname, key)
+
+    }, nil
+}
+
+// appendKnownHost records hostname's key in the known_hosts-formatted file at path, creating it
+// if necessary.
+func appendKnownHost(path, hostname string, key ssh.PublicKey) error {
+
+    f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+    if err != nil {
+        return fmt.Errorf("failed to open known_hosts file %s. Here's why: %w", path, err)
+    }
+    defer f.Close()
+
+    line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+    if _, err := f.WriteString(line + "\n"); err != nil {
+        return fmt.Errorf("failed to append to known_hosts file %s. Here's why: %w", path, err)
+    }
+
+    return nil
+}
+
+// pinnedFingerprintCallback accepts only keys whose SHA256 fingerprint is in pinned.
+func pinnedFingerprintCallback(pinned []string) ssh.HostKeyCallback {
+
+    allowed := make(map[string]bool, len(pinned))
+    for _, fp := range pinned {
+        allowed[fp] = true
+    }
+
+    return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+        fingerprint := ssh.FingerprintSHA256(key)
+        if allowed[fingerprint] {
+            return nil
+        }
+        return &HostKeyMismatchError{Host: hostname, GotFingerprint: fingerprint}
+    }
+}
@@ -0,0 +1,194 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TunnelStatus is a point-in-time snapshot of one tunnel managed by a TunnelManager.
+type TunnelStatus struct {
+    Name              string
+    Mode              Mode
+    Server            *Endpoint
+    Local             *Endpoint
+    Remote            *Endpoint
+    Healthy           bool
+    LastConnected     time.Time
+    ActiveConnections int
+}
+
+// managedTunnel bundles a running SSHTunnel with the means to stop it independently of its
+// siblings in the same TunnelManager.
+type managedTunnel struct {
+    cfg    TunnelConfig
+    tunnel *SSHTunnel
+    cancel context.CancelFunc
+}
+
+// TunnelManager runs a set of named SSHTunnels concurrently under one parent context, and lets
+// callers add, remove, inspect and reload them at runtime.
+type TunnelManager struct {
+    ctx context.Context
+
+    mu      sync.RWMutex
+    tunnels map[string]*managedTunnel
+}
+
+// NewTunnelManager creates a TunnelManager. Every tunnel it starts is a child of ctx: cancelling
+// ctx stops all of them at once.
+func NewTunnelManager(ctx context.Context) *TunnelManager {
+    return &TunnelManager{
+        ctx:     ctx,
+        tunnels: make(map[string]*managedTunnel),
+    }
+}
+
+// Add builds and starts a tunnel from cfg. It's an error to Add a name that's already present;
+// callers that want to replace one should Remove it first (Reload does this automatically).
+func (m *TunnelManager) Add(cfg TunnelConfig) error {
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, exists := m.tunnels[cfg.Name]; exists {
+        return fmt.Errorf("tunnel manager: %q is already running", cfg.Name)
+    }
+
+    t, err := cfg.Build()
+    if err != nil {
+        return fmt.Errorf("tunnel manager: failed to build %q. Here's why: %w", cfg.Name, err)
+    }
+
+    tunnelCtx, cancel := context.WithCancel(m.ctx)
+    go func() {
+        if err := t.Start(tunnelCtx); err != nil {
+            log.Printf("\ntunnel %q exited. Here's why: %v", cfg.Name, err)
+        }
+    }()
+
+    if err := t.WaitReady(tunnelCtx); err != nil {
+        cancel()
+        return fmt.Errorf("tunnel manager: %q failed to become ready. Here's why: %w", cfg.Name, err)
+    }
+
+    m.tunnels[cfg.Name] = &managedTunnel{cfg: cfg, tunnel: t, cancel: cancel}
+    return nil
+}
+
+// Remove drains and stops the named tunnel, then forgets about it.
+func (m *TunnelManager) Remove(name string) error {
+
+    m.mu.Lock()
+    mt, exists := m.tunnels[name]
+    if !exists {
+        m.mu.Unlock()
+        return fmt.Errorf("tunnel manager: %q is not running", name)
+    }
+    delete(m.tunnels, name)
+    m.mu.Unlock()
+
+    shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), mt.tunnel.Opts.ShutdownTimeout)
+    defer cancelShutdown()
+    if err := mt.tunnel.Shutdown(shutdownCtx); err != nil {
+        log.Printf("\ntunnel %q: %v", name, err)
+    }
+    mt.cancel()
+    return nil
+}
+
+// Status returns a snapshot of every managed tunnel.
+func (m *TunnelManager) Status() []TunnelStatus {
+
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    statuses := make([]TunnelStatus, 0, len(m.tunnels))
+    for name, mt := range m.tunnels {
+        statuses = append(statuses, TunnelStatus{
+            Name:              name,
+            Mode:              mt.tunnel.Mode,
+            Server:            mt.tunnel.Server,
+            Local:             mt.tunnel.Local,
+            Remote:            mt.tunnel.Remote,
+            Healthy:           mt.tunnel.Healthy(),
+            LastConnected:     mt.tunnel.LastConnected,
+            ActiveConnections: mt.tunnel.ActiveConnections(),
+        })
+    }
+    return statuses
+}
+
+// Lookup returns the current status of the named tunnel.
+func (m *TunnelManager) Lookup(name string) (TunnelStatus, bool) {
+
+    m.mu.RLock()
+    mt, exists := m.tunnels[name]
+    m.mu.RUnlock()
+
+    if !exists {
+        return TunnelStatus{}, false
+    }
+
+    return TunnelStatus{
+        Name:              name,
+        Mode:              mt.tunnel.Mode,
+        Server:            mt.tunnel.Server,
+        Local:             mt.tunnel.Local,
+        Remote:            mt.tunnel.Remote,
+        Healthy:           mt.tunnel.Healthy(),
+        LastConnected:     mt.tunnel.LastConnected,
+        ActiveConnections: mt.tunnel.ActiveConnections(),
+    }, true
+}
+
+// Reload reads the config file at path and reconciles the running tunnels against it: tunnels
+// missing from the file are removed, tunnels present but changed are restarted, and new entries
+// are added. It returns the first error encountered but keeps going, so a single bad entry
+// doesn't block the rest of the reload.
+func (m *TunnelManager) Reload(path string) error {
+
+    configs, err := LoadConfig(path)
+    if err != nil {
+        return err
+    }
+
+    wanted := make(map[string]TunnelConfig, len(configs))
+    for _, cfg := range configs {
+        wanted[cfg.Name] = cfg
+    }
+
+    m.mu.RLock()
+    var toRemove []string
+    for name, mt := range m.tunnels {
+        cfg, stillWanted := wanted[name]
+        if !stillWanted || !reflect.DeepEqual(cfg, mt.cfg) {
+            toRemove = append(toRemove, name)
+        }
+    }
+    m.mu.RUnlock()
+
+    var firstErr error
+    for _, name := range toRemove {
+        if err := m.Remove(name); err != nil && firstErr == nil {
+            firstErr = err
+        }
+    }
+
+    for _, cfg := range configs {
+        m.mu.RLock()
+        _, running := m.tunnels[cfg.Name]
+        m.mu.RUnlock()
+        if running {
+            continue
+        }
+        if err := m.Add(cfg); err != nil && firstErr == nil {
+            firstErr = err
+        }
+    }
+
+    return firstErr
+}
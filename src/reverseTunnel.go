@@ -0,0 +1,125 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// NewReverseSSHTunnel creates a new reverse-mode SSHTunnel instance: it asks Server to listen on
+// remoteBind and forwards every connection it accepts there back to dialAddr on this host.
+// tunnelAddress is in the form "user@host[:port]".
+// remoteBind is the address (host:port) for Server to listen on, e.g. "0.0.0.0:8080".
+// dialAddr is the local address (host:port) each accepted remote connection is forwarded to.
+// opts may be nil, in which case DefaultTunnelOptions is used.
+func NewReverseSSHTunnel(tunnelAddress string, auth ssh.AuthMethod, remoteBind string, dialAddr string, opts *TunnelOptions) (*SSHTunnel, error) {
+
+    t, err := newTunnel(tunnelAddress, auth, opts)
+    if err != nil {
+        return nil, err
+    }
+
+    t.Remote = NewEndpoint(remoteBind)
+    t.Local = NewEndpoint(dialAddr)
+    t.Mode = ModeReverse
+
+    return t, nil
+}
+
+// startReverse waits for the connection supervisor to bring up the remote listener and then
+// blocks until ctx is cancelled or the supervisor hits a fatal, non-retryable error.
+func (t *SSHTunnel) startReverse(ctx context.Context) error {
+
+    // Keep serverConn populated, run the remote listener, and reconnect on failure for the
+    // lifetime of the tunnel.
+    go t.superviseConnection(ctx)
+
+    select {
+    case <-t.readyCh:
+    case <-t.fatalCh:
+        t.mu.RLock()
+        defer t.mu.RUnlock()
+        return t.fatalErr
+    case <-ctx.Done():
+        return nil
+    }
+
+    select {
+    case <-t.fatalCh:
+        t.mu.RLock()
+        defer t.mu.RUnlock()
+        return t.fatalErr
+    case <-ctx.Done():
+        return nil
+    }
+}
+
+// runReverseAccept asks client to listen on t.Remote and forwards every accepted connection to
+// t.Local. It returns when the listener fails, ctx is cancelled, or the connection dies.
+func (t *SSHTunnel) runReverseAccept(ctx context.Context, client *ssh.Client) error {
+
+    listener, err := client.Listen("tcp", t.Remote.String())
+    if err != nil {
+        return fmt.Errorf("failed to listen on %s via %s. Here's why: %w", t.Remote.String(), t.Server.String(), err)
+    }
+    defer listener.Close()
+
+    go func() {
+        <-ctx.Done()
+        listener.Close()
+    }()
+
+    fmt.Printf("Listening on %s via %s [1 / 2]\n", t.Remote.String(), t.Server.String())
+    t.readyOnce.Do(func() { close(t.readyCh) })
+
+    for {
+        conn, err := listener.Accept()
+        if err != nil {
+            select {
+            case <-ctx.Done():
+                return nil
+            default:
+                return err
+            }
+        }
+
+        fmt.Printf("Connection accepted.\n")
+        go t.forwardReverse(conn)
+    }
+}
+
+// forwardReverse handles a single connection accepted on the remote listener: it dials t.Local
+// and sets up bidirectional copying.
+func (t *SSHTunnel) forwardReverse(remoteConn net.Conn) {
+
+    t.connWG.Add(1)
+    defer t.connWG.Done()
+
+    untrack := t.trackConn(remoteConn)
+    defer untrack()
+
+    defer remoteConn.Close()
+
+    localConn, err := net.Dial("tcp", t.Local.String())
+    if err != nil {
+        log.Printf("\nError while dialing local target %s. Here's why: %v", t.Local.String(), err)
+        return
+    }
+    defer localConn.Close()
+
+    fmt.Printf("Connected to %s [2 / 2]\n", t.Local.String())
+
+    go func() {
+        if _, err := io.Copy(remoteConn, localConn); err != nil {
+            log.Printf("Error copying from local to remote: %v", err)
+        }
+    }()
+
+    if _, err := io.Copy(localConn, remoteConn); err != nil {
+        log.Printf("Error copying from remote to local: %v", err)
+    }
+}
@@ -2,13 +2,16 @@ package tunnel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math/rand"
 	"net"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -35,7 +38,7 @@ func NewEndpoint(s string) *Endpoint{
     if parts := strings.Split(endpoint.Host, "@"); len(parts) > 1 {
         endpoint.User = parts[0]
         endpoint.Host = parts[1]
-    }  
+    }
 
     if parts := strings.Split(endpoint.Host, ":"); len(parts) > 1 {
         endpoint.Host = parts[0]
@@ -51,23 +54,85 @@ func (endpoint Endpoint) String() string{
     return fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
 }
 
+// TunnelOptions configures the reconnect and liveness behavior of an SSHTunnel.
+// A zero value is not ready to use; call DefaultTunnelOptions and override as needed.
+type TunnelOptions struct {
+    KeepAliveInterval time.Duration // How often to send the keepalive@openssh.com global request.
+    KeepAliveTimeout  time.Duration // How long to wait for a keepalive reply before considering the connection dead.
+    InitialBackoff    time.Duration // Backoff before the first reconnect attempt.
+    MaxBackoff        time.Duration // Upper bound the backoff is allowed to grow to.
+    FailFast          bool          // If true, connections arriving while reconnecting fail immediately instead of waiting.
+
+    HostKeyMode        HostKeyMode // How the key presented by Server is verified. Defaults to InsecureIgnoreHostKey.
+    KnownHostsFiles    []string    // Used when HostKeyMode is KnownHosts.
+    PinnedFingerprints []string    // SHA256 fingerprints (as returned by ssh.FingerprintSHA256), used when HostKeyMode is PinnedFingerprint.
+    TOFUFile           string      // When set under KnownHosts mode, unknown hosts are appended here instead of rejected.
+
+    ShutdownTimeout time.Duration // How long Shutdown waits for in-flight connections to drain before force-closing them.
+}
+
+// DefaultTunnelOptions returns the TunnelOptions used when NewSSHTunnel is called with nil options.
+func DefaultTunnelOptions() TunnelOptions {
+    return TunnelOptions{
+        KeepAliveInterval: 30 * time.Second,
+        KeepAliveTimeout:  35 * time.Second,
+        InitialBackoff:    500 * time.Millisecond,
+        MaxBackoff:        30 * time.Second,
+        FailFast:          false,
+
+        // Matches the tunnel's previous unconditional behavior. Callers that care about
+        // host key verification must opt into KnownHosts or PinnedFingerprint explicitly.
+        HostKeyMode: InsecureIgnoreHostKey,
+
+        ShutdownTimeout: 10 * time.Second,
+    }
+}
+
+// Mode selects which direction an SSHTunnel forwards traffic.
+type Mode int
+
+const (
+    ModeForward Mode = iota // Local listener -> Server -> Remote. See NewSSHTunnel.
+    ModeReverse              // Server listener -> local dial target. See NewReverseSSHTunnel.
+)
+
 // SSHTunnel encapsulates configuration and state for an SSH tunnel.
 type SSHTunnel struct {
 
-    Local  *Endpoint            // Local listening endpoint.
+    Mode   Mode                 // Forward or reverse. Set by NewSSHTunnel/NewReverseSSHTunnel.
+    Local  *Endpoint            // Local listening endpoint (forward mode) or local dial target (reverse mode).
     Server *Endpoint            // SSH tunnel server (jump host).
-    Remote *Endpoint            // Final destination endpoint.
+    Remote *Endpoint            // Final destination (forward mode) or bind address on Server (reverse mode).
     Config *ssh.ClientConfig    // SSH Client configuration.
+    Opts   TunnelOptions        // Reconnect/keepalive behavior.
     readyCh chan struct{}       // Signaling channel for when the tunnel is ready.
-}
+    readyOnce sync.Once         // Guards closing readyCh from multiple connection generations (reverse mode).
 
-// NewSSHTunnel creates a new SSHTunnel instance.
-// tunnelAddress is in the form "user@host[:port]".
-// destination is the address (host:port) to connect from the server.
-func NewSSHTunnel(tunnelAddress string, auth ssh.AuthMethod, destination string) *SSHTunnel{
+    mu            sync.RWMutex  // Guards serverConn, waitCh, LastConnected, listenerClosed and fatalErr below.
+    serverConn    *ssh.Client   // Current live SSH connection to Server, nil while reconnecting.
+    waitCh        chan struct{} // Open while reconnecting; closed the moment serverConn becomes healthy again.
+    LastConnected time.Time     // Timestamp of the most recent successful connection to Server.
 
-    // Use port 0 to have the system choose a random free port.
-    localEndpoint := NewEndpoint("localhost:0")
+    // listenerClosed is closed once the forward-mode local listener has actually finished
+    // closing, so Shutdown can wait for the port to really be free (e.g. before recycleTunnel
+    // rebinds it) instead of racing the async close triggered by ctx cancellation. Already closed
+    // at construction time and for reverse-mode tunnels, which own no local listener.
+    listenerClosed chan struct{}
+
+    fatalCh  chan struct{} // Closed if the supervisor hits an error it will never retry past, e.g. a host key mismatch.
+    fatalErr error         // Set before fatalCh is closed.
+
+    cancel context.CancelFunc // Cancels the internal context passed to startForward/startReverse/superviseConnection. Set once, by Start.
+
+    connWG  sync.WaitGroup     // One entry per in-flight forward()/forwardReverse() goroutine.
+    connsMu sync.Mutex         // Guards conns below.
+    conns   map[net.Conn]struct{} // Connections currently being forwarded, so Shutdown can force-close them.
+}
+
+// newTunnel builds the parts of an SSHTunnel shared by forward and reverse mode: the server
+// endpoint, ssh.ClientConfig (including host key verification) and lifecycle channels. Callers
+// fill in Local, Remote and Mode themselves.
+func newTunnel(tunnelAddress string, auth ssh.AuthMethod, opts *TunnelOptions) (*SSHTunnel, error) {
 
     // Default ssh to port 22
     server := NewEndpoint(tunnelAddress)
@@ -75,47 +140,176 @@ func NewSSHTunnel(tunnelAddress string, auth ssh.AuthMethod, destination string)
         server.Port = 22
     }
 
+    tunnelOpts := DefaultTunnelOptions()
+    if opts != nil {
+        tunnelOpts = *opts
+    }
+
+    hostKeyCallback, err := buildHostKeyCallback(tunnelOpts)
+    if err != nil {
+        return nil, fmt.Errorf("failed to configure host key verification. Here's why: %w", err)
+    }
+
+    // No local listener exists yet (and reverse mode never has one), so Shutdown has nothing to
+    // wait for until startForward installs a fresh channel.
+    listenerClosed := make(chan struct{})
+    close(listenerClosed)
+
     return &SSHTunnel{
-        Local: localEndpoint,
         Server: server,
-        Remote: NewEndpoint(destination),
         Config: &ssh.ClientConfig{
             User: server.User,
             Auth: []ssh.AuthMethod{auth},
-
-            // What is this?
-            HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
-
-                // Always accept key.
-                return nil
-            },
+            HostKeyCallback: hostKeyCallback,
             Timeout: 5 * time.Second, // Set a dial timeout for SSH connection.
         },
+        Opts: tunnelOpts,
         readyCh: make(chan struct{}),
+        waitCh: make(chan struct{}),
+        fatalCh: make(chan struct{}),
+        conns: make(map[net.Conn]struct{}),
+        listenerClosed: listenerClosed,
+    }, nil
+}
+
+// NewSSHTunnel creates a new forward-mode SSHTunnel instance.
+// tunnelAddress is in the form "user@host[:port]".
+// destination is the address (host:port) to connect from the server.
+// opts may be nil, in which case DefaultTunnelOptions is used.
+func NewSSHTunnel(tunnelAddress string, auth ssh.AuthMethod, destination string, opts *TunnelOptions) (*SSHTunnel, error){
+
+    t, err := newTunnel(tunnelAddress, auth, opts)
+    if err != nil {
+        return nil, err
     }
+
+    // Use port 0 to have the system choose a random free port.
+    t.Local = NewEndpoint("localhost:0")
+    t.Remote = NewEndpoint(destination)
+    t.Mode = ModeForward
+
+    return t, nil
 }
 
-// Start launches the SSH tunnel. It listens for incoming connections on the locally bound port,
-// signals readiness via readyCh, and forwards connections. The method monitors the provided
-// context and will shut down gracefully when the context is cancelled.
+// Start launches the SSH tunnel, dispatching to the forward or reverse implementation depending
+// on t.Mode. It signals readiness via readyCh and monitors the provided context, shutting down
+// gracefully when the context is cancelled.
 func (t *SSHTunnel) Start(ctx context.Context) error {
 
+    internalCtx, cancel := context.WithCancel(ctx)
+    t.mu.Lock()
+    t.cancel = cancel
+    t.mu.Unlock()
+    defer cancel()
+
+    if t.Mode == ModeReverse {
+        return t.startReverse(internalCtx)
+    }
+    return t.startForward(internalCtx)
+}
+
+// Shutdown stops the tunnel from accepting new connections or reconnecting to Server, waits for
+// the local listener (forward mode) to actually finish closing so its port is free for reuse, and
+// waits for in-flight forwarded connections to finish. Connections still open when ctx is done are
+// force-closed. Shutdown returns nil once every connection has drained, or an error naming how
+// many were force-closed otherwise.
+func (t *SSHTunnel) Shutdown(ctx context.Context) error {
+
+    t.mu.RLock()
+    cancel := t.cancel
+    listenerClosed := t.listenerClosed
+    t.mu.RUnlock()
+    if cancel != nil {
+        cancel()
+    }
+
+    drained := make(chan struct{})
+    go func() {
+        <-listenerClosed
+        t.connWG.Wait()
+        close(drained)
+    }()
+
+    select {
+    case <-drained:
+        return nil
+    case <-ctx.Done():
+        n := t.forceCloseConns()
+        <-drained // Closed connections make their forward goroutines return promptly.
+        return fmt.Errorf("tunnel: shutdown deadline exceeded, force-closed %d connection(s)", n)
+    }
+}
+
+// trackConn registers conn as in-flight and returns a function that unregisters it. Callers must
+// defer the returned function.
+func (t *SSHTunnel) trackConn(conn net.Conn) func() {
+    t.connsMu.Lock()
+    t.conns[conn] = struct{}{}
+    t.connsMu.Unlock()
+
+    return func() {
+        t.connsMu.Lock()
+        delete(t.conns, conn)
+        t.connsMu.Unlock()
+    }
+}
+
+// forceCloseConns closes every currently tracked connection and returns how many it closed.
+func (t *SSHTunnel) forceCloseConns() int {
+    t.connsMu.Lock()
+    defer t.connsMu.Unlock()
+
+    n := len(t.conns)
+    for conn := range t.conns {
+        conn.Close()
+    }
+    return n
+}
+
+// ActiveConnections reports how many connections are currently being forwarded.
+func (t *SSHTunnel) ActiveConnections() int {
+    t.connsMu.Lock()
+    defer t.connsMu.Unlock()
+    return len(t.conns)
+}
+
+// startForward listens for incoming connections on the locally bound port and forwards each one,
+// through Server, to Remote. A background supervisor keeps the connection to Server alive with a
+// keepalive watchdog and reconnects with exponential backoff on failure.
+func (t *SSHTunnel) startForward(ctx context.Context) error {
+
     listener, err := net.Listen("tcp", t.Local.String())
     if err != nil {
-        return fmt.Errorf("failed to listen on %s. Here's why: %w", t.Local.String(), err)
+        err = fmt.Errorf("failed to listen on %s. Here's why: %w", t.Local.String(), err)
+        t.setFatal(err) // Wake WaitReady, which would otherwise wait for a readyCh that never closes.
+        return err
     }
 
-    // When the context is cancelled, close the listener so Accept() returns.
+    closed := make(chan struct{})
+    t.mu.Lock()
+    t.listenerClosed = closed
+    t.mu.Unlock()
+
+    // Close the listener when the context is cancelled, or when the supervisor hits a fatal,
+    // non-retryable error (e.g. a host key mismatch) so Accept() returns either way. Shutdown
+    // waits on closed so it never returns before the port is actually free.
     go func(){
-        <-ctx.Done()
+        select {
+        case <-ctx.Done():
+        case <-t.fatalCh:
+        }
         listener.Close()
+        close(closed)
     }()
 
     // Set the actual port assigned.
     t.Local.Port = listener.Addr().(*net.TCPAddr).Port
 
     // Signal that the tunnel is ready.
-    close(t.readyCh)
+    t.readyOnce.Do(func() { close(t.readyCh) })
+
+    // Keep serverConn populated and healthy for the lifetime of the tunnel.
+    go t.superviseConnection(ctx)
 
     // Accept loop.
     for {
@@ -127,6 +321,12 @@ func (t *SSHTunnel) Start(ctx context.Context) error {
 
                 // Expected error due to listener being closed on shutdown.
                 return nil
+            case <-t.fatalCh:
+
+                t.mu.RLock()
+                fatalErr := t.fatalErr
+                t.mu.RUnlock()
+                return fatalErr
             default:
                 return fmt.Errorf("\nfailed to accept connection: %w", err)
 
@@ -134,34 +334,207 @@ func (t *SSHTunnel) Start(ctx context.Context) error {
         }
 
         fmt.Printf("Connection accepted.\n")
-        go t.forward(conn)
+        go t.forward(ctx, conn)
     }
 }
 
 func (t *SSHTunnel) WaitReady(ctx context.Context) error {
     select {
     case <-t.readyCh:
-        return nil
+        t.mu.RLock()
+        defer t.mu.RUnlock()
+        return t.fatalErr
+    case <-t.fatalCh:
+        t.mu.RLock()
+        defer t.mu.RUnlock()
+        return t.fatalErr
     case <-ctx.Done():
         return ctx.Err()
     }
 }
 
+// Healthy reports whether the tunnel currently holds a live SSH connection to Server.
+func (t *SSHTunnel) Healthy() bool {
+    t.mu.RLock()
+    defer t.mu.RUnlock()
+    return t.serverConn != nil
+}
+
+// superviseConnection dials Server, runs a keepalive watchdog against the resulting client, and
+// reconnects whenever the connection is found dead, backing off exponentially while dialing fails,
+// until ctx is cancelled.
+func (t *SSHTunnel) superviseConnection(ctx context.Context) {
+
+    backoff := t.Opts.InitialBackoff
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        default:
+        }
+
+        client, err := ssh.Dial("tcp", t.Server.String(), t.Config)
+        if err != nil {
+
+            var hostKeyErr *HostKeyMismatchError
+            if errors.As(err, &hostKeyErr) {
+                // Not a transient failure: the remote end presented a key we don't trust.
+                // Stop retrying rather than hammering a possibly-hostile server.
+                log.Printf("\nRefusing to connect to %s: %v", t.Server.String(), err)
+                t.setFatal(err)
+                return
+            }
+
+            log.Printf("\nFailed to connect to %s. Here's why: %v. Retrying in %s.", t.Server.String(), err, backoff)
+            if !sleepWithContext(ctx, withJitter(backoff)) {
+                return
+            }
+            backoff = nextBackoff(backoff, t.Opts.MaxBackoff)
+            continue
+        }
+
+        fmt.Printf("Connected to %s [1 / 2]\n", t.Server.String())
+        t.setConnected(client)
+        backoff = t.Opts.InitialBackoff
+
+        genCtx, genCancel := context.WithCancel(ctx)
+        var reverseWG sync.WaitGroup
+        if t.Mode == ModeReverse {
+            reverseWG.Add(1)
+            go func() {
+                defer reverseWG.Done()
+                if err := t.runReverseAccept(genCtx, client); err != nil {
+                    log.Printf("\nReverse listener on %s stopped. Here's why: %v", t.Remote.String(), err)
+                }
+            }()
+        }
+
+        // Blocks until the keepalive watchdog decides the connection is dead or ctx is cancelled.
+        t.watchKeepAlive(ctx, client)
+
+        genCancel()
+        reverseWG.Wait()
+
+        t.setDisconnected()
+        client.Close()
+
+        select {
+        case <-ctx.Done():
+            return
+        default:
+        }
+    }
+}
+
+// watchKeepAlive periodically sends the keepalive@openssh.com global request on client and
+// returns as soon as a reply is not received within KeepAliveTimeout, the request itself fails,
+// or ctx is cancelled.
+func (t *SSHTunnel) watchKeepAlive(ctx context.Context, client *ssh.Client) {
+
+    ticker := time.NewTicker(t.Opts.KeepAliveInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+
+            replied := make(chan error, 1)
+            go func() {
+                _, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+                replied <- err
+            }()
+
+            select {
+            case err := <-replied:
+                if err != nil {
+                    log.Printf("\nKeepalive to %s failed. Here's why: %v", t.Server.String(), err)
+                    return
+                }
+            case <-time.After(t.Opts.KeepAliveTimeout):
+                log.Printf("\nKeepalive to %s timed out after %s.", t.Server.String(), t.Opts.KeepAliveTimeout)
+                return
+            case <-ctx.Done():
+                return
+            }
+        }
+    }
+}
+
+// setConnected publishes a newly dialed client and unblocks any forward() calls waiting on it.
+func (t *SSHTunnel) setConnected(client *ssh.Client) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.serverConn = client
+    t.LastConnected = time.Now()
+    close(t.waitCh)
+}
+
+// setFatal records err as the tunnel's terminal error and wakes up anyone blocked in WaitReady,
+// Start's accept loop, or getConnection.
+func (t *SSHTunnel) setFatal(err error) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.fatalErr = err
+    close(t.fatalCh)
+}
+
+// setDisconnected clears the current client and arms a fresh waitCh for the next reconnect.
+func (t *SSHTunnel) setDisconnected() {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.serverConn = nil
+    t.waitCh = make(chan struct{})
+}
+
+// getConnection returns the current live SSH connection to Server. While reconnecting it either
+// blocks until a connection becomes available or fails fast, depending on Opts.FailFast.
+func (t *SSHTunnel) getConnection(ctx context.Context) (*ssh.Client, error) {
+
+    t.mu.RLock()
+    client := t.serverConn
+    waitCh := t.waitCh
+    t.mu.RUnlock()
 
-// forward handles a single connection: it establishes an SSH connection from the tunnel
-// server to the remote endpoint and then sets up bidirectional copying.
-func (tunnel *SSHTunnel) forward(localConn net.Conn) {
+    if client != nil {
+        return client, nil
+    }
+
+    if t.Opts.FailFast {
+        return nil, fmt.Errorf("tunnel: no live connection to %s (fail-fast)", t.Server.String())
+    }
+
+    select {
+    case <-waitCh:
+        return t.getConnection(ctx)
+    case <-t.fatalCh:
+        t.mu.RLock()
+        defer t.mu.RUnlock()
+        return nil, t.fatalErr
+    case <-ctx.Done():
+        return nil, ctx.Err()
+    }
+}
+
+// forward handles a single connection: it borrows the tunnel's current SSH connection to the
+// server, dials the remote endpoint from there, and sets up bidirectional copying.
+func (tunnel *SSHTunnel) forward(ctx context.Context, localConn net.Conn) {
+
+    tunnel.connWG.Add(1)
+    defer tunnel.connWG.Done()
+
+    untrack := tunnel.trackConn(localConn)
+    defer untrack()
 
     defer localConn.Close()
-    
-    // Dial the SSH server
-    serverConn, err := ssh.Dial("tcp", tunnel.Server.String(), tunnel.Config)
+
+    serverConn, err := tunnel.getConnection(ctx)
     if err != nil {
-        log.Fatalf("\nError while dialing server. Here's why: %v", err)
+        log.Printf("\nError while acquiring connection to %s. Here's why: %v", tunnel.Server.String(), err)
+        return
     }
-    defer serverConn.Close()
-
-    fmt.Printf("Connected to %s [1 / 2]\n", tunnel.Server.String())
 
     // From the SSH server, dial the remote destination.
     remoteConn, err := serverConn.Dial("tcp", tunnel.Remote.String())
@@ -189,6 +562,34 @@ func (tunnel *SSHTunnel) forward(localConn net.Conn) {
 
 }
 
+// sleepWithContext sleeps for d or returns early if ctx is cancelled. It reports whether the
+// sleep completed without cancellation.
+func sleepWithContext(ctx context.Context, d time.Duration) bool {
+    timer := time.NewTimer(d)
+    defer timer.Stop()
+    select {
+    case <-timer.C:
+        return true
+    case <-ctx.Done():
+        return false
+    }
+}
+
+// nextBackoff doubles backoff, capped at max.
+func nextBackoff(backoff, max time.Duration) time.Duration {
+    backoff *= 2
+    if backoff > max {
+        backoff = max
+    }
+    return backoff
+}
+
+// withJitter returns d plus up to 50% extra, to avoid every reconnecting tunnel hammering the
+// server in lockstep.
+func withJitter(d time.Duration) time.Duration {
+    return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
 func Private_key_file(path string) ssh.AuthMethod {
 
     buffer, err := os.ReadFile(path)